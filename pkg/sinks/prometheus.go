@@ -2,12 +2,19 @@ package sinks
 
 import (
 	"context"
+	"fmt"
+	"net/http"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"k8s.io/utils/strings/slices"
 
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/resmoio/kubernetes-event-exporter/pkg/kube"
 	"github.com/rs/zerolog/log"
 )
@@ -18,28 +25,194 @@ var (
 	camelCaseRegex               = regexp.MustCompile("([a-z0-9])([A-Z])")
 )
 
-func newGaugeVec(opts prometheus.GaugeOpts, labelNames []string) *prometheus.GaugeVec {
-	v := prometheus.NewGaugeVec(opts, labelNames)
-	prometheus.MustRegister(v)
-	return v
+const (
+	MetricTypeGauge     = "gauge"
+	MetricTypeCounter   = "counter"
+	MetricTypeHistogram = "histogram"
+
+	ValueFromCount       = ".Count"
+	ValueFromDuration    = ".firstTimestamp-.lastTimestamp"
+	valueFromRegexPrefix = "regex:"
+)
+
+// PrometheusMetric is the per-series handle returned by a PrometheusMetricVec's With. Only the
+// method matching the vec's MetricType is ever called.
+type PrometheusMetric interface {
+	Set(float64)
+	Add(float64)
+	Observe(float64)
+}
+
+// PrometheusMetricVec abstracts over GaugeVec, CounterVec, and HistogramVec so Send can
+// dispatch on MetricType without caring which concrete type backs a kind.
+type PrometheusMetricVec interface {
+	With(labels prometheus.Labels) PrometheusMetric
+	Delete(labels prometheus.Labels) bool
+}
+
+type gaugeMetric struct{ prometheus.Gauge }
+
+func (m gaugeMetric) Observe(float64) { panic("sinks: Observe called on a gauge metric") }
+
+type gaugeMetricVec struct{ *prometheus.GaugeVec }
+
+func (v gaugeMetricVec) With(labels prometheus.Labels) PrometheusMetric {
+	return gaugeMetric{v.GaugeVec.With(labels)}
+}
+
+type counterMetric struct{ prometheus.Counter }
+
+func (m counterMetric) Set(float64)     { panic("sinks: Set called on a counter metric") }
+func (m counterMetric) Observe(float64) { panic("sinks: Observe called on a counter metric") }
+
+type counterMetricVec struct{ *prometheus.CounterVec }
+
+func (v counterMetricVec) With(labels prometheus.Labels) PrometheusMetric {
+	return counterMetric{v.CounterVec.With(labels)}
+}
+
+type histogramMetric struct{ prometheus.Observer }
+
+func (m histogramMetric) Set(float64) { panic("sinks: Set called on a histogram metric") }
+func (m histogramMetric) Add(float64) { panic("sinks: Add called on a histogram metric") }
+
+type histogramMetricVec struct{ *prometheus.HistogramVec }
+
+func (v histogramMetricVec) With(labels prometheus.Labels) PrometheusMetric {
+	return histogramMetric{v.HistogramVec.With(labels)}
+}
+
+// metricTypeRegistry rejects registering the same metric name twice under a conflicting
+// MetricType with a descriptive error, before the less specific error from Register.
+type metricTypeRegistry struct {
+	typesByName map[string]string
+}
+
+func newMetricTypeRegistry() *metricTypeRegistry {
+	return &metricTypeRegistry{typesByName: map[string]string{}}
+}
+
+func (r *metricTypeRegistry) register(name, metricType string) error {
+	if existing, ok := r.typesByName[name]; ok && existing != metricType {
+		return fmt.Errorf("metric %q was already registered as %q, cannot re-register as %q", name, existing, metricType)
+	}
+	r.typesByName[name] = metricType
+	return nil
+}
+
+func newMetricVec(typeGuard *metricTypeRegistry, reg prometheus.Registerer, metricType, name, help string, buckets []float64, labelNames []string) (PrometheusMetricVec, error) {
+	if err := typeGuard.register(name, metricType); err != nil {
+		return nil, err
+	}
+
+	switch metricType {
+	case "", MetricTypeGauge:
+		v := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: name, Help: help}, labelNames)
+		if err := reg.Register(v); err != nil {
+			return nil, fmt.Errorf("registering metric %q: %w", name, err)
+		}
+		return gaugeMetricVec{v}, nil
+	case MetricTypeCounter:
+		v := prometheus.NewCounterVec(prometheus.CounterOpts{Name: name, Help: help}, labelNames)
+		if err := reg.Register(v); err != nil {
+			return nil, fmt.Errorf("registering metric %q: %w", name, err)
+		}
+		return counterMetricVec{v}, nil
+	case MetricTypeHistogram:
+		if len(buckets) == 0 {
+			buckets = prometheus.DefBuckets
+		}
+		v := prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: name, Help: help, Buckets: buckets}, labelNames)
+		if err := reg.Register(v); err != nil {
+			return nil, fmt.Errorf("registering metric %q: %w", name, err)
+		}
+		return histogramMetricVec{v}, nil
+	default:
+		return nil, fmt.Errorf("unsupported metricType %q for metric %q", metricType, name)
+	}
+}
+
+// resolveValue computes the value to record for ev according to the kind's ValueFrom selector.
+func resolveValue(valueFrom string, valueFromRegex *regexp.Regexp, ev *kube.EnhancedEvent) (float64, error) {
+	switch {
+	case valueFrom == "" || valueFrom == ValueFromCount:
+		return float64(ev.Count), nil
+	case valueFrom == ValueFromDuration:
+		return ev.LastTimestamp.Sub(ev.FirstTimestamp.Time).Seconds(), nil
+	case valueFromRegex != nil:
+		match := valueFromRegex.FindStringSubmatch(ev.Message)
+		if len(match) < 2 {
+			return 0, fmt.Errorf("valueFrom regex %q did not match message %q", valueFromRegex.String(), ev.Message)
+		}
+		return strconv.ParseFloat(match[1], 64)
+	default:
+		return 0, fmt.Errorf("unsupported valueFrom selector %q", valueFrom)
+	}
 }
 
 type PrometheusConfig struct {
 	EventsMetricsNamePrefix string              `yaml:"eventsMetricsNamePrefix"`
 	ReasonFilter            map[string][]string `yaml:"reasonFilter"`
 	LabelFilter             map[string][]string `yaml:"labelFilter"`
+	// TTL expires a (kind, label-set) series that hasn't been re-sent within this duration,
+	// e.g. "5m". A zero value disables expiration.
+	TTL string `yaml:"ttl"`
+	// TTLCleanupInterval controls how often the TTL sweep runs. Defaults to TTL/2.
+	TTLCleanupInterval string `yaml:"ttlCleanupInterval"`
+	// MetricType selects the Prometheus metric type per kind: "gauge" (default), "counter",
+	// or "histogram".
+	MetricType map[string]string `yaml:"metricType"`
+	// Buckets configures histogram bucket boundaries per kind; defaults to prometheus.DefBuckets.
+	Buckets map[string][]float64 `yaml:"buckets"`
+	// ValueFrom selects the numeric value recorded per kind instead of the default ev.Count.
+	// Accepts ".Count", ".firstTimestamp-.lastTimestamp", or "regex:<pattern>".
+	ValueFrom map[string]string `yaml:"valueFrom"`
+	// Relabel applies Prometheus-style relabel rules to the assembled label set.
+	Relabel []*RelabelConfig `yaml:"relabel"`
+	// HonorLabels mirrors statsd_exporter's honor_labels option: when true, a LabelFilter
+	// entry that collides with a built-in label (name, namespace, reason) is dropped instead
+	// of overwriting it. Two entries that sanitize to the same metric label are rejected by
+	// NewPrometheusSink whatever this is set to.
+	HonorLabels map[string]bool `yaml:"honorLabels"`
+	// Registry is where metrics are registered. Defaults to a fresh prometheus.NewRegistry()
+	// per sink so multiple PrometheusSink instances can coexist. Programmatic only.
+	Registry *prometheus.Registry `yaml:"-"`
+	// ListenAddress, when set, starts an embedded HTTP server serving Registry at /metrics.
+	ListenAddress string `yaml:"listenAddress"`
 }
 
-type PrometheusGaugeVec interface {
-	With(labels prometheus.Labels) prometheus.Gauge
-	Delete(labels prometheus.Labels) bool
+// seriesState tracks per (kind, label-set) series bookkeeping: when it was last observed
+// (TTL eviction) and, for counters, the last value recorded (to compute the next Add delta).
+type seriesState struct {
+	lastSeen  time.Time
+	lastValue float64
+	kind      string
+	labels    prometheus.Labels
 }
 
+// PrometheusSink is safe for concurrent use: the by-kind maps below are built once in
+// NewPrometheusSink and never written again; Send only mutates the sync.Map-backed
+// seriesLastSeen, so no lock is needed on the hot path.
 type PrometheusSink struct {
-	cfg                *PrometheusConfig
-	kinds              []string
-	metricsByKind      map[string]PrometheusGaugeVec
-	metricLabelsByKind map[string][]string
+	cfg                  *PrometheusConfig
+	kinds                []string
+	metricsByKind        map[string]PrometheusMetricVec
+	metricLabelsByKind   map[string][]string
+	honorLabelsByKind    map[string]bool
+	metricTypeByKind     map[string]string
+	valueFromByKind      map[string]string
+	valueFromRegexByKind map[string]*regexp.Regexp
+	relabelConfigs       []*RelabelConfig
+	labelCollisions      *prometheus.CounterVec
+	registry             *prometheus.Registry
+
+	ttl            time.Duration
+	ttlInterval    time.Duration
+	seriesLastSeen sync.Map // map[string]*seriesState
+	nowFunc        func() time.Time
+	stopCleanup    chan struct{}
+	cleanupStopped chan struct{}
+	httpServer     *http.Server
 }
 
 func NewPrometheusSink(config *PrometheusConfig) (Sink, error) {
@@ -47,49 +220,175 @@ func NewPrometheusSink(config *PrometheusConfig) (Sink, error) {
 		config.EventsMetricsNamePrefix = "event_exporter_"
 	}
 
-	metricsByKind := map[string]PrometheusGaugeVec{}
+	metricsByKind := map[string]PrometheusMetricVec{}
 	metricLabelsByKind := map[string][]string{}
+	honorLabelsByKind := map[string]bool{}
+	metricTypeByKind := map[string]string{}
+	valueFromByKind := map[string]string{}
+	valueFromRegexByKind := map[string]*regexp.Regexp{}
+	typeGuard := newMetricTypeRegistry()
+
+	registry := config.Registry
+	if registry == nil {
+		registry = prometheus.NewRegistry()
+	}
 
 	log.Info().Msgf("Initializing new Prometheus sink...")
 	kinds := []string{}
 	for kind := range config.ReasonFilter {
 		kinds = append(kinds, kind)
-		labels := defaultMetricLabels
+		honorLabelsByKind[kind] = config.HonorLabels[kind]
+
+		// labels is everything Send needs (including entries that collide with
+		// name/namespace/reason, resolved at Send-time via HonorLabels); vecLabelNames is
+		// the deduplicated set actually registered on the metric Vec.
+		labels := append([]string{}, defaultMetricLabels...)
+		customLabelOwners := map[string]string{}
+		vecLabelNames := append([]string{}, defaultMetricLabels...)
 		if config.LabelFilter[kind] != nil {
 			for _, label := range config.LabelFilter[kind] {
-				if !slices.Contains(defaultMetricLabels, label) {
+				if slices.Contains(defaultMetricLabels, label) {
 					labels = append(labels, label)
+					continue
 				}
+				name := getMetricLabelName(label)
+				if owner, ok := customLabelOwners[name]; ok {
+					if owner == label {
+						continue // same label requested twice
+					}
+					return nil, fmt.Errorf("prometheus sink: kind %q labels %q and %q both sanitize to metric label %q; rename one or drop it from labelFilter", kind, owner, label, name)
+				}
+				customLabelOwners[name] = label
+				labels = append(labels, label)
+				vecLabelNames = append(vecLabelNames, label)
 			}
 		}
 		metricLabelsByKind[kind] = labels
 
+		metricType := config.MetricType[kind]
+		metricTypeByKind[kind] = metricType
+
+		valueFrom := config.ValueFrom[kind]
+		valueFromByKind[kind] = valueFrom
+		if strings.HasPrefix(valueFrom, valueFromRegexPrefix) {
+			pattern := strings.TrimPrefix(valueFrom, valueFromRegexPrefix)
+			compiled, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid valueFrom regex %q for kind %q: %w", pattern, kind, err)
+			}
+			valueFromRegexByKind[kind] = compiled
+		}
+
 		metricName := config.EventsMetricsNamePrefix + strings.ToLower(kind) + "_event_count"
-		metricsByKind[kind] = newGaugeVec(
-			prometheus.GaugeOpts{
-				Name: metricName,
-				Help: "Event counts for " + kind + " resources.",
-			}, getMetricLabelNames(labels))
+		metric, err := newMetricVec(typeGuard, registry, metricType, metricName, "Event counts for "+kind+" resources.", config.Buckets[kind], getMetricLabelNames(vecLabelNames))
+		if err != nil {
+			return nil, err
+		}
+		metricsByKind[kind] = metric
 
 		log.Info().Msgf("Created metric: %s, will emit events: %v with additional labels: %v", kind, config.ReasonFilter[kind], labels)
 	}
 
-	return &PrometheusSink{
-		cfg:                config,
-		kinds:              kinds,
-		metricsByKind:      metricsByKind,
-		metricLabelsByKind: metricLabelsByKind,
-	}, nil
+	labelCollisions := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: config.EventsMetricsNamePrefix + "label_collisions_total",
+		Help: "Number of times a source label collided with a built-in or another metric label and was resolved by overwriting.",
+	}, []string{"kind", "label"})
+	if err := registry.Register(labelCollisions); err != nil {
+		return nil, fmt.Errorf("registering label collisions counter: %w", err)
+	}
+
+	if err := compileRelabelConfigs(config.Relabel); err != nil {
+		return nil, err
+	}
+
+	var ttl, ttlInterval time.Duration
+	if config.TTL != "" {
+		var err error
+		ttl, err = time.ParseDuration(config.TTL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ttl %q: %w", config.TTL, err)
+		}
+		if config.TTLCleanupInterval != "" {
+			ttlInterval, err = time.ParseDuration(config.TTLCleanupInterval)
+			if err != nil {
+				return nil, fmt.Errorf("invalid ttlCleanupInterval %q: %w", config.TTLCleanupInterval, err)
+			}
+		} else {
+			ttlInterval = ttl / 2
+		}
+		if ttl > 0 && ttlInterval <= 0 {
+			return nil, fmt.Errorf("ttlCleanupInterval must be positive, got %s (derived from ttl %s)", ttlInterval, config.TTL)
+		}
+	}
+
+	o := &PrometheusSink{
+		cfg:                  config,
+		kinds:                kinds,
+		metricsByKind:        metricsByKind,
+		metricLabelsByKind:   metricLabelsByKind,
+		honorLabelsByKind:    honorLabelsByKind,
+		metricTypeByKind:     metricTypeByKind,
+		valueFromByKind:      valueFromByKind,
+		valueFromRegexByKind: valueFromRegexByKind,
+		relabelConfigs:       config.Relabel,
+		labelCollisions:      labelCollisions,
+		registry:             registry,
+		ttl:                  ttl,
+		ttlInterval:          ttlInterval,
+		nowFunc:              time.Now,
+	}
+
+	if o.ttl > 0 {
+		o.stopCleanup = make(chan struct{})
+		o.cleanupStopped = make(chan struct{})
+		go o.runTTLCleanup()
+	}
+
+	if config.ListenAddress != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", o.Handler())
+		o.httpServer = &http.Server{Addr: config.ListenAddress, Handler: mux}
+		go func() {
+			if err := o.httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				log.Error().Err(err).Msg("prometheus sink: metrics server failed")
+			}
+		}()
+	}
+
+	return o, nil
+}
+
+// Handler serves the sink's registry, in the OpenMetrics exposition format when the scraper
+// asks for it, for callers that want to mount it on their own HTTP server instead of using
+// ListenAddress.
+func (o *PrometheusSink) Handler() http.Handler {
+	return promhttp.HandlerFor(o.registry, promhttp.HandlerOpts{EnableOpenMetrics: true})
 }
 
 func (o *PrometheusSink) Send(ctx context.Context, ev *kube.EnhancedEvent) error {
 	kind := ev.InvolvedObject.Kind
 	if slices.Contains(o.kinds, kind) {
 		for _, reason := range o.cfg.ReasonFilter[kind] {
+			labels, collisions := getMetricLabels(o.metricLabelsByKind[kind], ev.InvolvedObject, reason, o.honorLabelsByKind[kind])
+			if !o.honorLabelsByKind[kind] {
+				for _, name := range collisions {
+					o.labelCollisions.WithLabelValues(kind, name).Inc()
+				}
+			}
+
+			labels, keep := applyRelabelConfigs(labels, o.relabelConfigs)
+			if !keep {
+				continue
+			}
+
 			if ev.Reason == reason {
-				SetEventCount(o.metricsByKind[kind], o.metricLabelsByKind[kind], ev.InvolvedObject, reason, ev.Count)
+				if err := o.recordEvent(kind, ev, labels); err != nil {
+					return err
+				}
 			} else {
-				DeleteEventCount(o.metricsByKind[kind], o.metricLabelsByKind[kind], ev.InvolvedObject, reason)
+				log.Info().Msgf("Deleting event count metric with labels: %v", labels)
+				o.metricsByKind[kind].Delete(labels)
+				o.forgetSeries(kind, labels)
 			}
 		}
 	}
@@ -97,15 +396,134 @@ func (o *PrometheusSink) Send(ctx context.Context, ev *kube.EnhancedEvent) error
 	return nil
 }
 
+// recordEvent dispatches ev's resolved value to the kind's metric according to its
+// MetricType: gauges are Set, counters are Add'd as a delta, histograms are Observed.
+func (o *PrometheusSink) recordEvent(kind string, ev *kube.EnhancedEvent, labels prometheus.Labels) error {
+	value, err := resolveValue(o.valueFromByKind[kind], o.valueFromRegexByKind[kind], ev)
+	if err != nil {
+		return fmt.Errorf("resolving value for kind %q: %w", kind, err)
+	}
+
+	metric := o.metricsByKind[kind].With(labels)
+	log.Info().Msgf("Recording metric with labels: %v, value: %v", labels, value)
+
+	switch o.metricTypeByKind[kind] {
+	case MetricTypeCounter:
+		delta := value - o.lastValue(kind, labels)
+		if delta > 0 {
+			metric.Add(delta)
+		}
+	case MetricTypeHistogram:
+		metric.Observe(value)
+	default:
+		metric.Set(value)
+	}
+
+	o.touchSeries(kind, labels, value)
+	return nil
+}
+
+// lastValue returns the last recorded value for (kind, labels), or 0 if unseen.
+func (o *PrometheusSink) lastValue(kind string, labels prometheus.Labels) float64 {
+	if v, ok := o.seriesLastSeen.Load(seriesKey(kind, labels)); ok {
+		return v.(*seriesState).lastValue
+	}
+	return 0
+}
+
+// touchSeries records (kind, labels) as last seen now with the given value.
+func (o *PrometheusSink) touchSeries(kind string, labels prometheus.Labels, value float64) {
+	o.seriesLastSeen.Store(seriesKey(kind, labels), &seriesState{
+		lastSeen:  o.now(),
+		lastValue: value,
+		kind:      kind,
+		labels:    labels,
+	})
+}
+
+// now falls back to time.Now when a PrometheusSink is constructed directly (e.g. in tests)
+// instead of through NewPrometheusSink, where nowFunc is always set.
+func (o *PrometheusSink) now() time.Time {
+	if o.nowFunc != nil {
+		return o.nowFunc()
+	}
+	return time.Now()
+}
+
+// forgetSeries removes the bookkeeping entry for a series that was just explicitly deleted.
+func (o *PrometheusSink) forgetSeries(kind string, labels prometheus.Labels) {
+	o.seriesLastSeen.Delete(seriesKey(kind, labels))
+}
+
+func (o *PrometheusSink) runTTLCleanup() {
+	defer close(o.cleanupStopped)
+	ticker := time.NewTicker(o.ttlInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-o.stopCleanup:
+			return
+		case <-ticker.C:
+			o.evictStaleSeries()
+		}
+	}
+}
+
+func (o *PrometheusSink) evictStaleSeries() {
+	now := o.now()
+	o.seriesLastSeen.Range(func(key, value interface{}) bool {
+		state := value.(*seriesState)
+		if now.Sub(state.lastSeen) < o.ttl {
+			return true
+		}
+		if metric, ok := o.metricsByKind[state.kind]; ok {
+			metric.Delete(state.labels)
+		}
+		o.seriesLastSeen.Delete(key)
+		return true
+	})
+}
+
+// seriesKey builds a stable identity for a (kind, label-set) series by sorting the label
+// pairs before joining them, so the same logical series always hashes to the same key
+// regardless of map iteration order.
+func seriesKey(kind string, labels prometheus.Labels) string {
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString(kind)
+	for _, name := range names {
+		b.WriteByte('\x00')
+		b.WriteString(name)
+		b.WriteByte('=')
+		b.WriteString(labels[name])
+	}
+	return b.String()
+}
+
 func (o *PrometheusSink) Close() {
-	// No-op
+	if o.stopCleanup != nil {
+		close(o.stopCleanup)
+		<-o.cleanupStopped
+	}
+	if o.httpServer != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := o.httpServer.Shutdown(ctx); err != nil {
+			log.Error().Err(err).Msg("prometheus sink: metrics server shutdown failed")
+		}
+	}
 }
 
 func sanitizeLabelName(label string) string {
 	// Uses kube-state-metrics label name format
 	// See https://github.com/kubernetes/kube-state-metrics/blob/9ba1c3702142918e09e8eb5ca530e15198624259/internal/store/utils.go#L125
 	label = invalidCharsRegex.ReplaceAllString(label, "_")
-	label = camelCaseRegex.ReplaceAllString(label, "${1}_{2}")
+	label = camelCaseRegex.ReplaceAllString(label, "${1}_${2}")
 	return strings.ToLower(label)
 }
 
@@ -127,30 +545,32 @@ func getMetricLabelNames(labels []string) []string {
 	return labelNames
 }
 
-func getMetricLabels(metricLabels []string, obj kube.EnhancedObjectReference, reason string) prometheus.Labels {
-	prometheusLabels := prometheus.Labels{
+// getMetricLabels assembles the prometheus.Labels for an event. metricLabels is
+// defaultMetricLabels followed by the kind's LabelFilter entries; any that collide with a
+// built-in label are reported back in collisions, and honorLabels decides whether the
+// existing value wins instead of being overwritten.
+func getMetricLabels(metricLabels []string, obj kube.EnhancedObjectReference, reason string, honorLabels bool) (labels prometheus.Labels, collisions []string) {
+	labels = prometheus.Labels{
 		"name":      obj.Name,
 		"namespace": obj.Namespace,
 		"reason":    reason,
 	}
 
-	for _, label := range metricLabels {
-		if !slices.Contains(defaultMetricLabels, label) {
-			prometheusLabels[getMetricLabelName(label)] = obj.Labels[label]
-		}
+	custom := metricLabels
+	if len(custom) >= len(defaultMetricLabels) {
+		custom = custom[len(defaultMetricLabels):]
 	}
 
-	return prometheusLabels
-}
-
-func SetEventCount(metric PrometheusGaugeVec, metricLabels []string, obj kube.EnhancedObjectReference, reason string, count int32) {
-	labels := getMetricLabels(metricLabels, obj, reason)
-	log.Info().Msgf("Setting event count metric with labels: %v", labels)
-	metric.With(labels).Set(float64(count))
-}
+	for _, label := range custom {
+		name := getMetricLabelName(label)
+		if _, collides := labels[name]; collides {
+			collisions = append(collisions, name)
+			if honorLabels {
+				continue
+			}
+		}
+		labels[name] = obj.Labels[label]
+	}
 
-func DeleteEventCount(metric PrometheusGaugeVec, metricLabels []string, obj kube.EnhancedObjectReference, reason string) {
-	labels := getMetricLabels(metricLabels, obj, reason)
-	log.Info().Msgf("Deleting event count metric with labels: %v", labels)
-	metric.Delete(labels)
+	return labels, collisions
 }
@@ -2,33 +2,39 @@ package sinks
 
 import (
 	"context"
+	"io"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/resmoio/kubernetes-event-exporter/pkg/kube"
 	"github.com/stretchr/testify/mock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-type mockGauge struct {
+type mockMetric struct {
 	mock.Mock
-	prometheus.Gauge
 }
 
-func (m *mockGauge) Set(count float64) {
-	m.Called(count)
-}
+func (m *mockMetric) Set(v float64)     { m.Called(v) }
+func (m *mockMetric) Add(v float64)     { m.Called(v) }
+func (m *mockMetric) Observe(v float64) { m.Called(v) }
 
-type mockGuageVec struct {
+type mockMetricVec struct {
 	mock.Mock
-	*prometheus.GaugeVec
 }
 
-func (v *mockGuageVec) With(labels prometheus.Labels) prometheus.Gauge {
+func (v *mockMetricVec) With(labels prometheus.Labels) PrometheusMetric {
 	withArgs := v.Called(labels)
-	return withArgs.Get(0).(prometheus.Gauge)
+	return withArgs.Get(0).(PrometheusMetric)
 }
 
-func (v *mockGuageVec) Delete(labels prometheus.Labels) bool {
+func (v *mockMetricVec) Delete(labels prometheus.Labels) bool {
 	deleteArgs := v.Called(labels)
 	return deleteArgs.Get(0).(bool)
 }
@@ -69,9 +75,9 @@ func TestPrometheusSink_Send(t *testing.T) {
 			configAdditionalLabel: configLabel,
 			ev:                    testEvent,
 			wantPrometheusLabels: prometheus.Labels{
-				"name":                  testEvent.InvolvedObject.Name,
-				"namespace":             testEvent.InvolvedObject.Namespace,
-				"reason":                configReason,
+				"name":                 testEvent.InvolvedObject.Name,
+				"namespace":            testEvent.InvolvedObject.Namespace,
+				"reason":               configReason,
 				"label_" + configLabel: testEvent.InvolvedObject.Labels[configLabel],
 			},
 			wantErr:          false,
@@ -85,9 +91,9 @@ func TestPrometheusSink_Send(t *testing.T) {
 			configAdditionalLabel: configLabel,
 			ev:                    testEvent,
 			wantPrometheusLabels: prometheus.Labels{
-				"name":                  testEvent.InvolvedObject.Name,
-				"namespace":             testEvent.InvolvedObject.Namespace,
-				"reason":                "Creating",
+				"name":                 testEvent.InvolvedObject.Name,
+				"namespace":            testEvent.InvolvedObject.Namespace,
+				"reason":               "Creating",
 				"label_" + configLabel: testEvent.InvolvedObject.Labels[configLabel],
 			},
 			wantErr:          false,
@@ -107,10 +113,10 @@ func TestPrometheusSink_Send(t *testing.T) {
 		},
 	}
 	for _, tt := range tests {
-		mockGauge := &mockGauge{}
-		mockGauge.On("Set", mock.Anything).Return()
-		mockPodMetric := &mockGuageVec{}
-		mockPodMetric.On("With", mock.Anything).Return(mockGauge)
+		mockMetric := &mockMetric{}
+		mockMetric.On("Set", mock.Anything).Return()
+		mockPodMetric := &mockMetricVec{}
+		mockPodMetric.On("With", mock.Anything).Return(mockMetric)
 		mockPodMetric.On("Delete", mock.Anything).Return(true)
 		additionalLabels := []string{tt.configAdditionalLabel}
 
@@ -122,7 +128,7 @@ func TestPrometheusSink_Send(t *testing.T) {
 					LabelFilter:             map[string][]string{tt.configKind: additionalLabels},
 				},
 				kinds:              []string{tt.configKind},
-				metricsByKind:      map[string]PrometheusGaugeVec{tt.configKind: mockPodMetric},
+				metricsByKind:      map[string]PrometheusMetricVec{tt.configKind: mockPodMetric},
 				metricLabelsByKind: map[string][]string{tt.configKind: append(defaultMetricLabels, additionalLabels...)},
 			}
 			if err := o.Send(context.TODO(), tt.ev); (err != nil) != tt.wantErr {
@@ -131,10 +137,10 @@ func TestPrometheusSink_Send(t *testing.T) {
 
 			if tt.wantSetCalled {
 				mockPodMetric.AssertCalled(t, "With", tt.wantPrometheusLabels)
-				mockGauge.AssertCalled(t, "Set", float64(1))
+				mockMetric.AssertCalled(t, "Set", float64(1))
 			} else {
 				mockPodMetric.AssertNotCalled(t, "With")
-				mockGauge.AssertNotCalled(t, "Set")
+				mockMetric.AssertNotCalled(t, "Set")
 			}
 
 			if tt.wantDeleteCalled {
@@ -145,3 +151,392 @@ func TestPrometheusSink_Send(t *testing.T) {
 		})
 	}
 }
+
+// fakeClock lets tests advance time deterministically instead of sleeping for the TTL.
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func TestPrometheusSink_TTLEviction(t *testing.T) {
+	kind := "Pod"
+	reason := "Starting"
+	ev := mockEvent(kind, "testpod", "testnamespace", nil, reason, 1)
+
+	mockMetric := &mockMetric{}
+	mockMetric.On("Set", mock.Anything).Return()
+	mockPodMetric := &mockMetricVec{}
+	mockPodMetric.On("With", mock.Anything).Return(mockMetric)
+	mockPodMetric.On("Delete", mock.Anything).Return(true)
+
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	o := &PrometheusSink{
+		cfg: &PrometheusConfig{
+			EventsMetricsNamePrefix: "test_prefix_",
+			ReasonFilter:            map[string][]string{kind: {reason}},
+		},
+		kinds:              []string{kind},
+		metricsByKind:      map[string]PrometheusMetricVec{kind: mockPodMetric},
+		metricLabelsByKind: map[string][]string{kind: defaultMetricLabels},
+		ttl:                5 * time.Minute,
+		nowFunc:            clock.Now,
+	}
+
+	if err := o.Send(context.TODO(), ev); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	// Not yet stale: sweeping now must not delete the series.
+	o.evictStaleSeries()
+	mockPodMetric.AssertNotCalled(t, "Delete", mock.Anything)
+
+	// Advance past the TTL and sweep again: the series must be deleted exactly once.
+	clock.Advance(6 * time.Minute)
+	o.evictStaleSeries()
+	o.evictStaleSeries()
+
+	mockPodMetric.AssertNumberOfCalls(t, "Delete", 1)
+	evictedLabels, _ := getMetricLabels(defaultMetricLabels, ev.InvolvedObject, reason, false)
+	if _, ok := o.seriesLastSeen.Load(seriesKey(kind, evictedLabels)); ok {
+		t.Errorf("expected series to be removed from seriesLastSeen after eviction")
+	}
+}
+
+func TestPrometheusSink_Send_CounterAddsDelta(t *testing.T) {
+	kind := "Pod"
+	reason := "Starting"
+
+	mockMetric := &mockMetric{}
+	mockMetric.On("Add", mock.Anything).Return()
+	mockPodMetric := &mockMetricVec{}
+	mockPodMetric.On("With", mock.Anything).Return(mockMetric)
+
+	o := &PrometheusSink{
+		cfg: &PrometheusConfig{
+			EventsMetricsNamePrefix: "test_prefix_",
+			ReasonFilter:            map[string][]string{kind: {reason}},
+		},
+		kinds:              []string{kind},
+		metricsByKind:      map[string]PrometheusMetricVec{kind: mockPodMetric},
+		metricLabelsByKind: map[string][]string{kind: defaultMetricLabels},
+		metricTypeByKind:   map[string]string{kind: MetricTypeCounter},
+		nowFunc:            time.Now,
+	}
+
+	ev1 := mockEvent(kind, "testpod", "testnamespace", nil, reason, 3)
+	if err := o.Send(context.TODO(), ev1); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	mockMetric.AssertCalled(t, "Add", float64(3))
+
+	ev2 := mockEvent(kind, "testpod", "testnamespace", nil, reason, 5)
+	if err := o.Send(context.TODO(), ev2); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	mockMetric.AssertCalled(t, "Add", float64(2))
+}
+
+func TestPrometheusSink_Send_HistogramObserves(t *testing.T) {
+	kind := "Pod"
+	reason := "Starting"
+	ev := mockEvent(kind, "testpod", "testnamespace", nil, reason, 7)
+
+	mockMetric := &mockMetric{}
+	mockMetric.On("Observe", mock.Anything).Return()
+	mockPodMetric := &mockMetricVec{}
+	mockPodMetric.On("With", mock.Anything).Return(mockMetric)
+
+	o := &PrometheusSink{
+		cfg: &PrometheusConfig{
+			EventsMetricsNamePrefix: "test_prefix_",
+			ReasonFilter:            map[string][]string{kind: {reason}},
+		},
+		kinds:              []string{kind},
+		metricsByKind:      map[string]PrometheusMetricVec{kind: mockPodMetric},
+		metricLabelsByKind: map[string][]string{kind: defaultMetricLabels},
+		metricTypeByKind:   map[string]string{kind: MetricTypeHistogram},
+		nowFunc:            time.Now,
+	}
+
+	if err := o.Send(context.TODO(), ev); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	mockMetric.AssertCalled(t, "Observe", float64(7))
+}
+
+func TestResolveValue(t *testing.T) {
+	ev := mockEvent("Pod", "testpod", "testnamespace", nil, "Starting", 9)
+	ev.FirstTimestamp = metav1.NewTime(time.Unix(100, 0))
+	ev.LastTimestamp = metav1.NewTime(time.Unix(142, 0))
+	ev.Message = "retrying after failures: 7"
+
+	tests := []struct {
+		name           string
+		valueFrom      string
+		valueFromRegex *regexp.Regexp
+		want           float64
+		wantErr        bool
+	}{
+		{
+			name:      "empty valueFrom defaults to Count",
+			valueFrom: "",
+			want:      9,
+		},
+		{
+			name:      "explicit .Count",
+			valueFrom: ValueFromCount,
+			want:      9,
+		},
+		{
+			name:      "duration subtracts firstTimestamp from lastTimestamp",
+			valueFrom: ValueFromDuration,
+			want:      42,
+		},
+		{
+			name:           "regex extracts the first capture group",
+			valueFrom:      "regex:failures: (\\d+)",
+			valueFromRegex: regexp.MustCompile(`failures: (\d+)`),
+			want:           7,
+		},
+		{
+			name:           "regex returns an error when the message doesn't match",
+			valueFrom:      "regex:attempts: (\\d+)",
+			valueFromRegex: regexp.MustCompile(`attempts: (\d+)`),
+			wantErr:        true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveValue(tt.valueFrom, tt.valueFromRegex, ev)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolveValue() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got != tt.want {
+				t.Errorf("resolveValue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewMetricVec_HistogramUsesCustomBuckets(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	metric, err := newMetricVec(newMetricTypeRegistry(), reg, MetricTypeHistogram, "test_custom_buckets", "help", []float64{1, 2, 3}, defaultMetricLabels)
+	if err != nil {
+		t.Fatalf("newMetricVec() error = %v", err)
+	}
+	metric.With(prometheus.Labels{"name": "a", "namespace": "b", "reason": "c"}).Observe(1.5)
+
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+	found := false
+	for _, mf := range families {
+		if mf.GetName() != "test_custom_buckets" {
+			continue
+		}
+		buckets := mf.GetMetric()[0].GetHistogram().GetBucket()
+		if len(buckets) != 3 {
+			t.Fatalf("expected 3 custom buckets, got %d", len(buckets))
+		}
+		found = true
+	}
+	if !found {
+		t.Fatal("expected to find the registered histogram in the registry")
+	}
+}
+
+func TestNewMetricVec_RejectsConflictingReregistration(t *testing.T) {
+	typeGuard := newMetricTypeRegistry()
+	reg := prometheus.NewRegistry()
+
+	if _, err := newMetricVec(typeGuard, reg, MetricTypeGauge, "test_reregister_event_count", "help", nil, defaultMetricLabels); err != nil {
+		t.Fatalf("newMetricVec() error = %v", err)
+	}
+
+	_, err := newMetricVec(typeGuard, reg, MetricTypeCounter, "test_reregister_event_count", "help", nil, defaultMetricLabels)
+	if err == nil {
+		t.Fatal("expected newMetricVec to reject re-registering the same metric name as a different MetricType")
+	}
+}
+
+// TestPrometheusSink_Send_ConcurrentOverlappingSeries spawns many goroutines firing Send for
+// a handful of overlapping (kind, name) series concurrently. All the sink's mutable
+// per-series bookkeeping lives in the sync.Map-backed seriesLastSeen, so this must complete
+// without a "fatal error: concurrent map writes" (run with -race to actually catch a
+// regression) and every series must end up at the value every writer agreed on.
+func TestPrometheusSink_Send_ConcurrentOverlappingSeries(t *testing.T) {
+	kind := "Pod"
+	reason := "Starting"
+	seriesNames := []string{"pod-a", "pod-b", "pod-c"}
+
+	gaugeVec := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "test_concurrent_event_count",
+		Help: "test",
+	}, getMetricLabelNames(defaultMetricLabels))
+
+	o := &PrometheusSink{
+		cfg: &PrometheusConfig{
+			EventsMetricsNamePrefix: "test_prefix_",
+			ReasonFilter:            map[string][]string{kind: {reason}},
+		},
+		kinds:              []string{kind},
+		metricsByKind:      map[string]PrometheusMetricVec{kind: gaugeMetricVec{gaugeVec}},
+		metricLabelsByKind: map[string][]string{kind: defaultMetricLabels},
+		ttl:                time.Minute,
+		nowFunc:            time.Now,
+	}
+
+	const goroutines = 50
+	const iterations = 20
+	var wg sync.WaitGroup
+	wg.Add(goroutines)
+	for i := 0; i < goroutines; i++ {
+		go func(i int) {
+			defer wg.Done()
+			name := seriesNames[i%len(seriesNames)]
+			for j := 0; j < iterations; j++ {
+				ev := mockEvent(kind, name, "testnamespace", nil, reason, 1)
+				if err := o.Send(context.TODO(), ev); err != nil {
+					t.Errorf("Send() error = %v", err)
+				}
+				o.evictStaleSeries()
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	for _, name := range seriesNames {
+		var objRef kube.EnhancedObjectReference
+		objRef.Name = name
+		objRef.Namespace = "testnamespace"
+		labels, _ := getMetricLabels(defaultMetricLabels, objRef, reason, false)
+		if got := testutil.ToFloat64(gaugeVec.With(labels)); got != 1 {
+			t.Errorf("gauge for %q = %v, want 1", name, got)
+		}
+	}
+}
+
+func TestGetMetricLabels_HonorLabels(t *testing.T) {
+	var obj kube.EnhancedObjectReference
+	obj.Name = "testpod"
+	obj.Namespace = "testnamespace"
+	// foo.bar and foo_bar both sanitize to "label_foo_bar": invalidCharsRegex replaces the dot
+	// with an underscore, making the two labels collide.
+	obj.Labels = map[string]string{"reason": "UserSuppliedReason", "foo.bar": "a", "foo_bar": "b"}
+	metricLabels := append(append([]string{}, defaultMetricLabels...), "reason", "foo.bar", "foo_bar")
+
+	t.Run("user label literally named reason is dropped when honoring built-ins", func(t *testing.T) {
+		labels, collisions := getMetricLabels(metricLabels, obj, "ActualReason", true)
+		if labels["reason"] != "ActualReason" {
+			t.Errorf(`labels["reason"] = %q, want "ActualReason"`, labels["reason"])
+		}
+		if len(collisions) == 0 {
+			t.Errorf("expected a reported collision for the built-in reason label")
+		}
+	})
+
+	t.Run("user label literally named reason overwrites when not honoring built-ins", func(t *testing.T) {
+		labels, collisions := getMetricLabels(metricLabels, obj, "ActualReason", false)
+		if labels["reason"] != "UserSuppliedReason" {
+			t.Errorf(`labels["reason"] = %q, want "UserSuppliedReason"`, labels["reason"])
+		}
+		if len(collisions) == 0 {
+			t.Errorf("expected a reported collision for the built-in reason label")
+		}
+	})
+
+	t.Run("two labels colliding after sanitizeLabelName honor the first", func(t *testing.T) {
+		labels, collisions := getMetricLabels(metricLabels, obj, "ActualReason", true)
+		if labels["label_foo_bar"] != "a" {
+			t.Errorf(`labels["label_foo_bar"] = %q, want "a" (first of the two colliding labels)`, labels["label_foo_bar"])
+		}
+		found := false
+		for _, name := range collisions {
+			if name == "label_foo_bar" {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("expected a reported collision for label_foo_bar, got %v", collisions)
+		}
+	})
+}
+
+func TestNewPrometheusSink_RejectsAmbiguousLabelCollision(t *testing.T) {
+	_, err := NewPrometheusSink(&PrometheusConfig{
+		EventsMetricsNamePrefix: "test_reject_ambiguous_",
+		ReasonFilter:            map[string][]string{"Pod": {"Starting"}},
+		// foo.bar and foo_bar both sanitize to "label_foo_bar", so this must fail in
+		// NewPrometheusSink's customLabelOwners check rather than later, e.g. when the
+		// Prometheus client itself rejects the duplicate label names at registration time.
+		LabelFilter: map[string][]string{"Pod": {"foo.bar", "foo_bar"}},
+	})
+	if err == nil {
+		t.Fatal("expected NewPrometheusSink to reject two labels that sanitize to the same metric label")
+	}
+	if !strings.Contains(err.Error(), "both sanitize to metric label") {
+		t.Fatalf("expected the customLabelOwners ambiguous-collision error, got: %v", err)
+	}
+}
+
+func TestNewPrometheusSink_RejectsNonPositiveTTLCleanupInterval(t *testing.T) {
+	_, err := NewPrometheusSink(&PrometheusConfig{
+		EventsMetricsNamePrefix: "test_reject_ttl_interval_",
+		ReasonFilter:            map[string][]string{"Pod": {"Starting"}},
+		TTL:                     "5m",
+		TTLCleanupInterval:      "0s",
+	})
+	if err == nil {
+		t.Fatal("expected NewPrometheusSink to reject a zero ttlCleanupInterval")
+	}
+}
+
+func TestNewPrometheusSink_SeparateRegistriesDontConflict(t *testing.T) {
+	cfg := func(prefix string) *PrometheusConfig {
+		return &PrometheusConfig{
+			EventsMetricsNamePrefix: prefix,
+			ReasonFilter:            map[string][]string{"Pod": {"Starting"}},
+		}
+	}
+
+	a, err := NewPrometheusSink(cfg("tenant_a_"))
+	if err != nil {
+		t.Fatalf("NewPrometheusSink tenant a error = %v", err)
+	}
+	defer a.(*PrometheusSink).Close()
+
+	b, err := NewPrometheusSink(cfg("tenant_b_"))
+	if err != nil {
+		t.Fatalf("NewPrometheusSink tenant b error = %v", err)
+	}
+	defer b.(*PrometheusSink).Close()
+
+	server := httptest.NewServer(a.(*PrometheusSink).Handler())
+	defer server.Close()
+
+	resp, err := server.Client().Get(server.URL)
+	if err != nil {
+		t.Fatalf("GET /metrics error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	buf := new(strings.Builder)
+	if _, err := io.Copy(buf, resp.Body); err != nil {
+		t.Fatalf("reading metrics response error = %v", err)
+	}
+	body := buf.String()
+	if strings.Contains(body, "tenant_b_") {
+		t.Errorf("expected tenant a's Handler not to expose tenant b's metrics, got body containing tenant_b_: %s", body)
+	}
+}
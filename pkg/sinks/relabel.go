@@ -0,0 +1,171 @@
+package sinks
+
+import (
+	"crypto/md5"
+	"encoding/binary"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	RelabelActionReplace   = "replace"
+	RelabelActionKeep      = "keep"
+	RelabelActionDrop      = "drop"
+	RelabelActionLabelMap  = "labelmap"
+	RelabelActionLabelDrop = "labeldrop"
+	RelabelActionLabelKeep = "labelkeep"
+	RelabelActionHashMod   = "hashmod"
+)
+
+// RelabelConfig mirrors a subset of Prometheus's relabel_config, applied to the assembled
+// prometheus.Labels for an event before it's recorded or deleted.
+type RelabelConfig struct {
+	SourceLabels []string `yaml:"sourceLabels"`
+	Separator    string   `yaml:"separator"`
+	Regex        string   `yaml:"regex"`
+	Modulus      uint64   `yaml:"modulus"`
+	TargetLabel  string   `yaml:"targetLabel"`
+	Replacement  string   `yaml:"replacement"`
+	Action       string   `yaml:"action"`
+
+	regex *regexp.Regexp
+}
+
+// compile fills in the Prometheus relabel_config defaults and compiles Regex once.
+func (c *RelabelConfig) compile() error {
+	if c.Separator == "" {
+		c.Separator = ";"
+	}
+	if c.Replacement == "" {
+		c.Replacement = "$1"
+	}
+	if c.Action == "" {
+		c.Action = RelabelActionReplace
+	}
+	switch c.Action {
+	case RelabelActionReplace, RelabelActionKeep, RelabelActionDrop, RelabelActionLabelMap,
+		RelabelActionLabelDrop, RelabelActionLabelKeep, RelabelActionHashMod:
+	default:
+		return fmt.Errorf("invalid relabel action %q", c.Action)
+	}
+	regex := c.Regex
+	if regex == "" {
+		regex = "(.*)"
+	}
+	compiled, err := regexp.Compile("^(?:" + regex + ")$")
+	if err != nil {
+		return fmt.Errorf("invalid relabel regex %q: %w", c.Regex, err)
+	}
+	c.regex = compiled
+	return nil
+}
+
+// compileRelabelConfigs compiles every config's regex up front so NewPrometheusSink can fail
+// fast on a bad relabel rule.
+func compileRelabelConfigs(configs []*RelabelConfig) error {
+	for _, c := range configs {
+		if err := c.compile(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyRelabelConfigs runs each RelabelConfig against labels in order, returning the
+// (possibly rewritten) label set, or keep=false if the event should be dropped entirely.
+func applyRelabelConfigs(labels prometheus.Labels, configs []*RelabelConfig) (result prometheus.Labels, keep bool) {
+	result = labels
+	for _, c := range configs {
+		result, keep = applyRelabelConfig(result, c)
+		if !keep {
+			return nil, false
+		}
+	}
+	return result, true
+}
+
+func applyRelabelConfig(labels prometheus.Labels, c *RelabelConfig) (prometheus.Labels, bool) {
+	switch c.Action {
+	case RelabelActionLabelMap:
+		return relabelLabelMap(labels, c), true
+	case RelabelActionLabelDrop:
+		return relabelLabelFilter(labels, c, false), true
+	case RelabelActionLabelKeep:
+		return relabelLabelFilter(labels, c, true), true
+	}
+
+	value := relabelSourceValue(labels, c)
+	match := c.regex.FindStringSubmatch(value)
+
+	switch c.Action {
+	case RelabelActionKeep:
+		return labels, match != nil
+	case RelabelActionDrop:
+		return labels, match == nil
+	case RelabelActionHashMod:
+		if c.Modulus == 0 {
+			return labels, true
+		}
+		sum := md5.Sum([]byte(value))
+		mod := binary.BigEndian.Uint64(sum[8:]) % c.Modulus
+		labels[c.TargetLabel] = strconv.FormatUint(mod, 10)
+		return labels, true
+	default: // replace
+		if match == nil {
+			return labels, true
+		}
+		labels[c.TargetLabel] = expandRelabelReplacement(c.Replacement, match)
+		return labels, true
+	}
+}
+
+// relabelSourceValue concatenates the values of SourceLabels with Separator.
+func relabelSourceValue(labels prometheus.Labels, c *RelabelConfig) string {
+	values := make([]string, len(c.SourceLabels))
+	for i, name := range c.SourceLabels {
+		values[i] = labels[name]
+	}
+	return strings.Join(values, c.Separator)
+}
+
+// expandRelabelReplacement substitutes $1..$9 backreferences in replacement with the
+// matching capture group.
+func expandRelabelReplacement(replacement string, match []string) string {
+	result := replacement
+	for i := len(match) - 1; i >= 1; i-- {
+		result = strings.ReplaceAll(result, "$"+strconv.Itoa(i), match[i])
+	}
+	return result
+}
+
+// relabelLabelMap copies every label whose name matches Regex to a new name expanded
+// from Replacement, leaving the original in place.
+func relabelLabelMap(labels prometheus.Labels, c *RelabelConfig) prometheus.Labels {
+	additions := prometheus.Labels{}
+	for name, value := range labels {
+		match := c.regex.FindStringSubmatch(name)
+		if match == nil {
+			continue
+		}
+		additions[expandRelabelReplacement(c.Replacement, match)] = value
+	}
+	for name, value := range additions {
+		labels[name] = value
+	}
+	return labels
+}
+
+// relabelLabelFilter drops labels by name: labeldrop removes names matching Regex (keep=false),
+// labelkeep removes names that don't match (keep=true).
+func relabelLabelFilter(labels prometheus.Labels, c *RelabelConfig, keepOnMatch bool) prometheus.Labels {
+	for name := range labels {
+		if c.regex.MatchString(name) != keepOnMatch {
+			delete(labels, name)
+		}
+	}
+	return labels
+}
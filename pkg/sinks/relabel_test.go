@@ -0,0 +1,127 @@
+package sinks
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestApplyRelabelConfigs(t *testing.T) {
+	tests := []struct {
+		name       string
+		labels     prometheus.Labels
+		config     *RelabelConfig
+		wantLabels prometheus.Labels
+		wantKeep   bool
+	}{
+		{
+			name:   "replace rewrites target label on match",
+			labels: prometheus.Labels{"name": "pod-abc123"},
+			config: &RelabelConfig{
+				Action:       RelabelActionReplace,
+				SourceLabels: []string{"name"},
+				Regex:        `pod-[a-z0-9]+`,
+				TargetLabel:  "name",
+				Replacement:  "pod",
+			},
+			wantLabels: prometheus.Labels{"name": "pod"},
+			wantKeep:   true,
+		},
+		{
+			name:   "replace leaves labels untouched when regex doesn't match",
+			labels: prometheus.Labels{"name": "service-x"},
+			config: &RelabelConfig{
+				Action:       RelabelActionReplace,
+				SourceLabels: []string{"name"},
+				Regex:        `pod-[a-z0-9]+`,
+				TargetLabel:  "name",
+				Replacement:  "pod",
+			},
+			wantLabels: prometheus.Labels{"name": "service-x"},
+			wantKeep:   true,
+		},
+		{
+			name:   "keep drops the event when regex doesn't match",
+			labels: prometheus.Labels{"namespace": "kube-system"},
+			config: &RelabelConfig{
+				Action:       RelabelActionKeep,
+				SourceLabels: []string{"namespace"},
+				Regex:        "default",
+			},
+			wantKeep: false,
+		},
+		{
+			name:   "drop removes the event when regex matches",
+			labels: prometheus.Labels{"namespace": "kube-system"},
+			config: &RelabelConfig{
+				Action:       RelabelActionDrop,
+				SourceLabels: []string{"namespace"},
+				Regex:        "kube-.*",
+			},
+			wantKeep: false,
+		},
+		{
+			name:   "labeldrop removes labels matching the name regex",
+			labels: prometheus.Labels{"name": "x", "label_internal_pod_hash": "abc"},
+			config: &RelabelConfig{
+				Action: RelabelActionLabelDrop,
+				Regex:  "label_internal.*",
+			},
+			wantLabels: prometheus.Labels{"name": "x"},
+			wantKeep:   true,
+		},
+		{
+			name:   "labelkeep removes labels not matching the name regex",
+			labels: prometheus.Labels{"name": "x", "label_internal_pod_hash": "abc"},
+			config: &RelabelConfig{
+				Action: RelabelActionLabelKeep,
+				Regex:  "name",
+			},
+			wantLabels: prometheus.Labels{"name": "x"},
+			wantKeep:   true,
+		},
+		{
+			name:   "labelmap copies matching label names via the replacement expansion",
+			labels: prometheus.Labels{"label_team": "infra"},
+			config: &RelabelConfig{
+				Action:      RelabelActionLabelMap,
+				Regex:       "label_(.*)",
+				Replacement: "mapped_$1",
+			},
+			wantLabels: prometheus.Labels{"label_team": "infra", "mapped_team": "infra"},
+			wantKeep:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.config.compile(); err != nil {
+				t.Fatalf("compile() error = %v", err)
+			}
+
+			gotLabels, gotKeep := applyRelabelConfigs(tt.labels, []*RelabelConfig{tt.config})
+			if gotKeep != tt.wantKeep {
+				t.Fatalf("applyRelabelConfigs() keep = %v, want %v", gotKeep, tt.wantKeep)
+			}
+			if !gotKeep {
+				return
+			}
+
+			if len(gotLabels) != len(tt.wantLabels) {
+				t.Fatalf("applyRelabelConfigs() labels = %v, want %v", gotLabels, tt.wantLabels)
+			}
+			for k, v := range tt.wantLabels {
+				if gotLabels[k] != v {
+					t.Errorf("applyRelabelConfigs() label %q = %q, want %q", k, gotLabels[k], v)
+				}
+			}
+		})
+	}
+}
+
+func TestRelabelConfig_Compile_RejectsUnknownAction(t *testing.T) {
+	c := &RelabelConfig{Action: "drpo"}
+	if err := c.compile(); err == nil {
+		t.Fatal("expected compile() to reject an unknown action")
+	}
+}